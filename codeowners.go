@@ -8,8 +8,11 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/bmatcuk/doublestar/v4"
 	"github.com/spf13/afero"
 )
 
@@ -17,12 +20,41 @@ import (
 type Codeowners struct {
 	repoRoot string
 	Patterns []Codeowner
+	// sections holds every section header declared in the file, in order,
+	// including ones with no pattern lines under them.
+	sections []*Section
 }
 
 // Codeowner - owners for a given pattern
 type Codeowner struct {
 	Pattern string
 	re      *regexp.Regexp
+	m       matcher
+	Owners  []string
+	// Section is the GitLab-style section this pattern was declared under,
+	// or nil if the pattern appears before any section header.
+	Section *Section
+	// Negate is true for a pattern prefixed with "!", which explicitly
+	// disowns the files it matches rather than assigning owners to them.
+	Negate bool
+	// Line is the 1-based line number the pattern was declared on.
+	Line int
+	// Dialect is how Pattern was interpreted, so ToFile can emit the
+	// "# syntax: ..." comment needed to round-trip a non-default dialect.
+	Dialect Dialect
+}
+
+// Section - a GitLab-style CODEOWNERS section, e.g. "[Database][2] @dba"
+type Section struct {
+	Name          string
+	Optional      bool
+	MinApprovers  int
+	DefaultOwners []string
+}
+
+// SectionMatch - the effective owners for a path within a Section
+type SectionMatch struct {
+	Section *Section
 	Owners  []string
 }
 
@@ -112,13 +144,89 @@ func FromFile(path string) (*Codeowners, error) {
 
 // FromReader creates a Codeowners from a given Reader instance and root path.
 func FromReader(r io.Reader, repoRoot string) (*Codeowners, error) {
+	return FromReaderWithOptions(r, repoRoot, Options{})
+}
+
+// Dialect selects how CODEOWNERS pattern fields are interpreted.
+type Dialect int
+
+const (
+	// DialectGitignore - patterns are gitignore-style globs (the default).
+	DialectGitignore Dialect = iota
+	// DialectRegex - patterns are Go regular expressions, as used by
+	// Gitea's CODEOWNERS variant.
+	DialectRegex
+)
+
+// String renders a Dialect the same way a "# syntax: ..." comment spells
+// it, so it can be emitted by ToFile and parsed back by parseSyntaxComment.
+func (d Dialect) String() string {
+	if d == DialectRegex {
+		return "regex"
+	}
+	return "gitignore"
+}
+
+// Options controls how a CODEOWNERS file is parsed.
+type Options struct {
+	Dialect Dialect
+}
+
+// FromFileWithOptions is FromFile with an explicit Options, e.g. to opt
+// into DialectRegex.
+func FromFileWithOptions(path string, opts Options) (*Codeowners, error) {
+	r, root, err := findCodeownersFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, fmt.Errorf("No CODEOWNERS found in %s", path)
+	}
+	return FromReaderWithOptions(r, root, opts)
+}
+
+// FromReaderWithOptions is FromReader with an explicit Options, e.g. to
+// opt into DialectRegex.
+func FromReaderWithOptions(r io.Reader, repoRoot string, opts Options) (*Codeowners, error) {
 	co := &Codeowners{
 		repoRoot: repoRoot,
 	}
-	co.Patterns = parseCodeowners(r)
+	co.Patterns, co.sections = parseCodeownersWithOptions(r, opts)
 	return co, nil
 }
 
+// FromDirectory creates a Codeowners the same way FromFile does, but also
+// scans every standard search location (., docs, .github, .gitlab) under
+// root for a CODEOWNERS file, so that the presence of more than one can be
+// reported as a lint Issue instead of findCodeownersFile silently picking
+// whichever one it finds first.
+func FromDirectory(root string) (*Codeowners, []Issue, error) {
+	var found []string
+	for _, p := range []string{".", "docs", ".github", ".gitlab"} {
+		f := path.Join(root, p, "CODEOWNERS")
+		if _, err := fs.Stat(f); err == nil {
+			found = append(found, f)
+		}
+	}
+
+	co, err := FromFile(root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	issues := co.Validate()
+	if len(found) > 1 {
+		issues = append(issues, Issue{
+			Severity: SeverityError,
+			Code:     CodeMultipleFiles,
+			Token:    strings.Join(found, ", "),
+			Message:  fmt.Sprintf("multiple CODEOWNERS files found: %s", strings.Join(found, ", ")),
+		})
+	}
+
+	return co, issues, nil
+}
+
 // ToFile - serialize the Codeowners to file
 func (c *Codeowners) ToFile(path string) (err error) {
 	f, err := os.Create(path)
@@ -133,29 +241,144 @@ func (c *Codeowners) ToFile(path string) (err error) {
 		return strings.ReplaceAll(pattern, " ", "\\ ")
 	}
 	w := bufio.NewWriter(f)
-	for _, c := range c.Patterns {
-		w.WriteString(fmt.Sprintf("%-25s %s\n", escape(c.Pattern), strings.Join(c.Owners, " ")))
+	lastDialect := DialectGitignore
+	writePattern := func(p Codeowner) {
+		if p.Dialect != lastDialect {
+			w.WriteString(fmt.Sprintf("# syntax: %s\n", p.Dialect))
+			lastDialect = p.Dialect
+		}
+		pattern := p.Pattern
+		if p.Negate {
+			pattern = "!" + pattern
+		}
+		w.WriteString(fmt.Sprintf("%-25s %s\n", escape(pattern), strings.Join(p.Owners, " ")))
+	}
+
+	// Patterns declared before any section header (including ones added
+	// later via AddPattern, which never sets Section) come first, then
+	// every section in declaration order - including ones with no pattern
+	// lines of their own, same as Sections() seeds those from c.sections.
+	for _, p := range c.Patterns {
+		if p.Section == nil {
+			writePattern(p)
+		}
+	}
+	for _, sec := range c.sections {
+		w.WriteString(formatSectionHeader(sec) + "\n")
+		for _, p := range c.Patterns {
+			if p.Section == sec {
+				writePattern(p)
+			}
+		}
 	}
 	err = w.Flush()
 	return
 }
 
+// formatSectionHeader - render a Section back into its GitLab-style header
+// line, e.g. "^[Frontend]" or "[Database][2] @dba"
+func formatSectionHeader(s *Section) string {
+	h := "[" + s.Name + "]"
+	if s.Optional {
+		h = "^" + h
+	}
+	if s.MinApprovers > 0 {
+		h = h + fmt.Sprintf("[%d]", s.MinApprovers)
+	}
+	if len(s.DefaultOwners) > 0 {
+		h = h + " " + strings.Join(s.DefaultOwners, " ")
+	}
+	return h
+}
+
+// sectionHeaderPattern matches GitLab-style section headers, e.g.
+// "[Backend]", "^[Frontend]" (optional) or "[Database][2] @dba"
+var sectionHeaderPattern = regexp.MustCompile(`^(\^)?\[([^\]]+)\](\[(\d+)\])?(\s+@.*)?$`)
+
+// parseSectionHeader - attempt to parse line as a section header, returning
+// the parsed Section and true if it is one.
+func parseSectionHeader(line string) (*Section, bool) {
+	m := sectionHeaderPattern.FindStringSubmatch(line)
+	if m == nil {
+		return nil, false
+	}
+	sec := &Section{
+		Name:     m[2],
+		Optional: m[1] == "^",
+	}
+	if m[4] != "" {
+		if n, err := strconv.Atoi(m[4]); err == nil {
+			sec.MinApprovers = n
+		}
+	}
+	if owners := strings.TrimSpace(m[5]); owners != "" {
+		sec.DefaultOwners = strings.Fields(owners)
+	}
+	return sec, true
+}
+
 // parseCodeowners parses a list of Codeowners from a Reader
 func parseCodeowners(r io.Reader) []Codeowner {
+	patterns, _ := parseCodeownersWithOptions(r, Options{})
+	return patterns
+}
+
+// syntaxCommentPattern matches a per-line dialect override, e.g.
+// "# syntax: regex", letting a mixed repo migrate gradually.
+var syntaxCommentPattern = regexp.MustCompile(`^#\s*syntax:\s*(\w+)\s*$`)
+
+// parseSyntaxComment - attempt to parse line as a "# syntax: <dialect>"
+// comment, returning the Dialect it selects and true if it is one.
+func parseSyntaxComment(line string) (Dialect, bool) {
+	m := syntaxCommentPattern.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return 0, false
+	}
+	switch strings.ToLower(m[1]) {
+	case "regex":
+		return DialectRegex, true
+	case "gitignore":
+		return DialectGitignore, true
+	default:
+		return 0, false
+	}
+}
+
+// parseCodeownersWithOptions parses a list of Codeowners from a Reader,
+// using opts.Dialect unless a "# syntax: ..." comment overrides it for the
+// remainder of the file. It also returns every section header declared in
+// the file, in order, even ones with no pattern lines under them.
+func parseCodeownersWithOptions(r io.Reader, opts Options) ([]Codeowner, []*Section) {
 	co := []Codeowner{}
+	sections := []*Section{}
+	var current *Section
+	dialect := opts.Dialect
+	lineNo := 0
 	s := bufio.NewScanner(r)
 	for s.Scan() {
-		fields := strings.Fields(s.Text())
+		lineNo++
+		line := s.Text()
+		fields := strings.Fields(line)
 		if len(fields) > 0 && strings.HasPrefix(fields[0], "#") {
+			if d, ok := parseSyntaxComment(line); ok {
+				dialect = d
+			}
+			continue
+		}
+		if sec, ok := parseSectionHeader(strings.TrimSpace(line)); ok {
+			current = sec
+			sections = append(sections, sec)
 			continue
 		}
-		if len(fields) > 1 {
+		if len(fields) > 0 {
 			fields = combineEscapedSpaces(fields)
-			c, _ := NewCodeowner(fields[0], fields[1:])
+			c, _ := newCodeowner(fields[0], fields[1:], dialect)
+			c.Section = current
+			c.Line = lineNo
 			co = append(co, c)
 		}
 	}
-	return co
+	return co, sections
 }
 
 // if any of the elements ends with a \, it was an escaped space
@@ -177,54 +400,496 @@ func combineEscapedSpaces(fields []string) []string {
 
 // NewCodeowner -
 func NewCodeowner(pattern string, owners []string) (Codeowner, error) {
-	re := getPattern(pattern)
+	return newCodeowner(pattern, owners, DialectGitignore)
+}
+
+// patternCompiler turns a raw pattern field into a matching regexp; which
+// one is used depends on the active Dialect.
+type patternCompiler func(string) *regexp.Regexp
+
+// compilerFor returns the patternCompiler for a Dialect.
+func compilerFor(d Dialect) patternCompiler {
+	if d == DialectRegex {
+		return compileRegexPattern
+	}
+	return getPattern
+}
+
+// compileRegexPattern treats pattern as a Go regular expression, matched
+// against the repo-relative path as-is. Used by DialectRegex.
+func compileRegexPattern(pattern string) *regexp.Regexp {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil
+	}
+	return re
+}
+
+// matcher tests whether a path matches a compiled pattern. Different
+// dialects/pattern shapes pick different implementations, see newMatcher.
+type matcher interface {
+	Match(path string) bool
+}
+
+// regexMatcher is the original, regexp-based matcher; it's kept as the
+// fallback for patterns doublestar can't express.
+type regexMatcher struct {
+	re *regexp.Regexp
+}
+
+func (m regexMatcher) Match(path string) bool {
+	return m.re != nil && m.re.MatchString(path)
+}
+
+// doublestarMatcher matches using github.com/bmatcuk/doublestar/v4, which
+// is far cheaper than a regexp per pattern per path at the scale of a
+// monorepo's CODEOWNERS file.
+type doublestarMatcher struct {
+	pattern string
+}
+
+func (m doublestarMatcher) Match(path string) bool {
+	ok, _ := doublestar.Match(m.pattern, strings.TrimPrefix(path, "/"))
+	return ok
+}
+
+// dirGlobExtPattern matches the "dir/*.ext" shape that getPattern anchors
+// to the repo root (by prepending "/") even though the pattern itself has
+// no leading "/"; toDoublestarPattern must recognize the same shape so it
+// doesn't turn that implicit anchoring into an any-depth match.
+var dirGlobExtPattern = regexp.MustCompile(`([^\/+])/.*\*\.`)
+
+// toDoublestarPattern converts a gitignore-style pattern into a doublestar
+// glob, or reports ok=false for constructs (escaped wildcards, "?", and
+// getPattern's "dir/*.ext" auto-anchoring) that are rare enough to leave
+// to the regexp fallback instead of reimplementing their exact gitignore
+// semantics in glob syntax. The returned pattern has no leading "/"; Match
+// strips it from the queried path the same way, so the two stay anchored
+// against each other.
+func toDoublestarPattern(pattern string) (string, bool) {
+	if strings.ContainsAny(pattern, `\?`) {
+		return "", false
+	}
+	p := pattern
+	anchored := strings.HasPrefix(p, "/")
+	if !anchored && dirGlobExtPattern.MatchString(p) {
+		return "", false
+	}
+	p = strings.TrimPrefix(p, "/")
+	if strings.HasSuffix(p, "/") {
+		p += "**"
+	}
+	if !anchored {
+		p = "**/" + p
+	}
+	return p, true
+}
+
+// newMatcher builds the matcher for a pattern under the given Dialect.
+// DialectRegex patterns are Go regexps and always use regexMatcher;
+// DialectGitignore patterns prefer doublestar and fall back to re.
+func newMatcher(pattern string, re *regexp.Regexp, d Dialect) matcher {
+	if d == DialectGitignore {
+		if dsPattern, ok := toDoublestarPattern(pattern); ok {
+			return doublestarMatcher{pattern: dsPattern}
+		}
+	}
+	return regexMatcher{re: re}
+}
+
+func newCodeowner(pattern string, owners []string, dialect Dialect) (Codeowner, error) {
+	negate := false
+	if strings.HasPrefix(pattern, "!") {
+		negate = true
+		pattern = pattern[1:]
+	}
+	re := compilerFor(dialect)(pattern)
 	c := Codeowner{
 		Pattern: pattern,
 		re:      re,
+		m:       newMatcher(pattern, re, dialect),
 		Owners:  owners,
+		Negate:  negate,
+		Dialect: dialect,
 	}
 	return c, nil
 }
 
-// Owners - return the list of code owners for the given path
-// (within the repo root)
-func (c *Codeowners) Owners(path string) []string {
+// Match - a single pattern that matched a queried path, in file order.
+type Match struct {
+	Pattern string
+	Owners  []string
+	Section *Section
+	Index   int
+}
+
+// AllOwners returns every pattern that matches path, in file order,
+// instead of just the last (winning) one. Downstream tools can use this to
+// union owners across sections, explain why a file is owned by someone, or
+// pick the most specific match by section rather than by last-wins.
+func (c *Codeowners) AllOwners(path string) []Match {
 	if strings.HasPrefix(path, c.repoRoot) {
 		path = strings.Replace(path, c.repoRoot, "", 1)
 	}
 
-	// Order is important; the last matching pattern takes the most precedence.
-	for i := len(c.Patterns) - 1; i >= 0; i-- {
-		p := c.Patterns[i]
-
-		if p.re.MatchString(path) {
-			return p.Owners
+	matches := []Match{}
+	for i, p := range c.Patterns {
+		if !p.m.Match(path) {
+			continue
 		}
+		matches = append(matches, Match{
+			Pattern: p.Pattern,
+			Owners:  p.Owners,
+			Section: p.Section,
+			Index:   i,
+		})
 	}
+	return matches
+}
 
-	return nil
+// Owners - return the list of code owners for the given path
+// (within the repo root)
+func (c *Codeowners) Owners(path string) []string {
+	all := c.AllOwners(path)
+	if len(all) == 0 {
+		return nil
+	}
+	// Order is important; the last matching pattern takes the most precedence.
+	last := all[len(all)-1]
+	if c.Patterns[last.Index].Negate {
+		return nil
+	}
+	return last.Owners
 }
 
 // LocalOwners - return the list of code owners for the given path
 // excluding the global owners
 // (within the repo root)
 func (c *Codeowners) LocalOwners(path string) []string {
+	all := c.AllOwners(path)
+
+	// Order is important; the last matching pattern takes the most precedence.
+	for i := len(all) - 1; i >= 0; i-- {
+		p := c.Patterns[all[i].Index]
+		if p.IsGlobal() {
+			continue
+		}
+		if p.Negate {
+			return nil
+		}
+		return all[i].Owners
+	}
+
+	return nil
+}
+
+// Explain formats the chain of matches for path and marks the winning
+// rule, for use in CLI tooling such as a "codeowners who" command.
+func (c *Codeowners) Explain(path string) string {
+	all := c.AllOwners(path)
+	if len(all) == 0 {
+		return fmt.Sprintf("%s: no matching pattern, no owners", path)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s:\n", path)
+	winner := all[len(all)-1].Index
+	for _, m := range all {
+		marker := "  "
+		if m.Index == winner {
+			marker = "->"
+		}
+		fmt.Fprintf(&b, "%s %s\t%s", marker, m.Pattern, strings.Join(m.Owners, ", "))
+		if c.Patterns[m.Index].Negate {
+			b.WriteString(" (negated)")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Sections - return, for each GitLab-style section defined in the
+// CODEOWNERS file, the effective owners for the given path. Within a
+// section the last matching pattern takes precedence, same as Owners;
+// if no pattern in a section matches, that section's DefaultOwners are
+// used instead.
+func (c *Codeowners) Sections(path string) map[string]SectionMatch {
 	if strings.HasPrefix(path, c.repoRoot) {
 		path = strings.Replace(path, c.repoRoot, "", 1)
 	}
 
-	// Order is important; the last matching pattern takes the most precedence.
+	matches := map[string]SectionMatch{}
+	for _, sec := range c.sections {
+		matches[sec.Name] = SectionMatch{Section: sec, Owners: sec.DefaultOwners}
+	}
+
+	resolved := map[string]bool{}
 	for i := len(c.Patterns) - 1; i >= 0; i-- {
 		p := c.Patterns[i]
-
-		if !p.IsGlobal() && p.re.MatchString(path) {
-			return p.Owners
+		if p.Section == nil || resolved[p.Section.Name] {
+			continue
+		}
+		if p.m.Match(path) {
+			owners := p.Owners
+			if p.Negate {
+				owners = nil
+			}
+			matches[p.Section.Name] = SectionMatch{Section: p.Section, Owners: owners}
+			resolved[p.Section.Name] = true
 		}
 	}
 
+	return matches
+}
+
+// SectionFor - return the effective owners for path within the named
+// section (see Sections), or nil if the section isn't defined.
+func (c *Codeowners) SectionFor(path, sectionName string) []string {
+	if m, ok := c.Sections(path)[sectionName]; ok {
+		return m.Owners
+	}
 	return nil
 }
 
+// literalPrefix returns the directory portion of pattern that precedes its
+// first wildcard, used to index patterns by the part of the path they
+// could possibly match. An unanchored pattern (no leading "/") can match
+// at any depth, same as getPattern's "(|.*/)" prefix, so it always
+// indexes under "/" rather than under its leading segment.
+func literalPrefix(pattern string) string {
+	if !strings.HasPrefix(pattern, "/") {
+		return "/"
+	}
+	p := strings.TrimPrefix(pattern, "/")
+	if i := strings.IndexAny(p, "*?"); i >= 0 {
+		p = p[:i]
+	}
+	if i := strings.LastIndex(p, "/"); i >= 0 {
+		return "/" + p[:i]
+	}
+	return "/"
+}
+
+// prefixIndex groups pattern indexes by literalPrefix so that OwnersMany
+// can skip matchers whose prefix isn't an ancestor of the queried path,
+// instead of running every matcher against every path.
+type prefixIndex struct {
+	byPrefix map[string][]int
+	prefixes []string // longest (most specific) first
+}
+
+func buildPrefixIndex(patterns []Codeowner) *prefixIndex {
+	idx := &prefixIndex{byPrefix: map[string][]int{}}
+	for i, p := range patterns {
+		prefix := literalPrefix(p.Pattern)
+		idx.byPrefix[prefix] = append(idx.byPrefix[prefix], i)
+	}
+	for prefix := range idx.byPrefix {
+		idx.prefixes = append(idx.prefixes, prefix)
+	}
+	sort.Slice(idx.prefixes, func(i, j int) bool {
+		return len(idx.prefixes[i]) > len(idx.prefixes[j])
+	})
+	return idx
+}
+
+// candidates returns the indexes of patterns whose prefix is an ancestor
+// of path, in descending pattern order (last-match-wins).
+func (idx *prefixIndex) candidates(path string) []int {
+	var out []int
+	for _, prefix := range idx.prefixes {
+		if prefix == "/" || strings.HasPrefix(path, prefix) {
+			out = append(out, idx.byPrefix[prefix]...)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(out)))
+	return out
+}
+
+// OwnersMany resolves owners for many paths in a single pass: the prefix
+// index over Patterns is built once, then each path only evaluates the
+// matchers whose literal prefix it could actually fall under, rather than
+// every pattern in the file.
+func (c *Codeowners) OwnersMany(paths []string) map[string][]string {
+	idx := buildPrefixIndex(c.Patterns)
+	result := make(map[string][]string, len(paths))
+
+	for _, path := range paths {
+		lookup := path
+		if strings.HasPrefix(lookup, c.repoRoot) {
+			lookup = strings.Replace(lookup, c.repoRoot, "", 1)
+		}
+
+		for _, i := range idx.candidates(lookup) {
+			p := c.Patterns[i]
+			if !p.m.Match(lookup) {
+				continue
+			}
+			if !p.Negate {
+				result[path] = p.Owners
+			}
+			break
+		}
+	}
+
+	return result
+}
+
+// Severity - how serious a lint Issue is
+type Severity int
+
+const (
+	// SeverityError - the CODEOWNERS file is broken or will misbehave
+	SeverityError Severity = iota
+	// SeverityWarning - the file is valid but probably not what was intended
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "unknown"
+	}
+}
+
+// Lint diagnostic codes, machine-readable so CI tooling can filter on them.
+const (
+	CodeMultipleFiles  = "E_MULTIPLE_FILES"
+	CodeInvalidOwner   = "E_INVALID_OWNER"
+	CodeInvalidPattern = "E_INVALID_PATTERN"
+	CodeDuplicate      = "W_DUPLICATE_PATTERN"
+	CodeUnreachable    = "W_UNREACHABLE"
+	CodeNoOwners       = "W_NO_OWNERS"
+)
+
+// Issue - a single diagnostic produced by Validate or Lint
+type Issue struct {
+	Severity Severity
+	Line     int
+	Token    string
+	Code     string
+	Message  string
+}
+
+func (i Issue) String() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("%s: line %d: %s (%s)", i.Severity, i.Line, i.Message, i.Code)
+	}
+	return fmt.Sprintf("%s: %s (%s)", i.Severity, i.Message, i.Code)
+}
+
+// ownerPattern matches a valid owner token: "@user", "@org/team" or an email
+var ownerPattern = regexp.MustCompile(`^(@[\w-]+(/[\w-]+)?|[^\s@]+@[^\s@]+\.[^\s@]+)$`)
+
+// Lint parses CODEOWNERS content from r and validates it, without needing a
+// Codeowners value or a file on disk.
+func Lint(r io.Reader) []Issue {
+	co := &Codeowners{Patterns: parseCodeowners(r)}
+	return co.Validate()
+}
+
+// isShadowedByLater reports whether later always wins over earlier: either
+// they're an identical pattern, later is a global "*"/"**", or later is a
+// recursive "dir/**" whose directory contains earlier's literal path (only
+// checked when earlier itself has no wildcards, so the containment check
+// stays exact).
+func isShadowedByLater(earlier, later Codeowner) bool {
+	if later.Pattern == earlier.Pattern || later.Pattern == "*" || later.Pattern == "**" {
+		return true
+	}
+	if strings.ContainsAny(earlier.Pattern, "*?") {
+		return false
+	}
+	dir := strings.TrimSuffix(later.Pattern, "**")
+	if dir == later.Pattern || !strings.HasSuffix(dir, "/") {
+		return false
+	}
+	// an anchored later pattern can't cover an unanchored earlier one, which
+	// also matches at depth
+	if strings.HasPrefix(later.Pattern, "/") && !strings.HasPrefix(earlier.Pattern, "/") {
+		return false
+	}
+	return strings.HasPrefix(strings.TrimPrefix(earlier.Pattern, "/"), strings.TrimPrefix(dir, "/"))
+}
+
+// Validate checks c for common mistakes: duplicate patterns, patterns
+// shadowed by an identical later pattern, by a later global "*"/"**", or
+// by a later recursive "dir/**" that fully contains them, rules with no
+// owners, malformed owner tokens and invalid pattern syntax. This is a
+// conservative subset of "unreachable" (it does not attempt general glob
+// subsumption), not an exhaustive reachability analysis. An empty slice
+// means no problems were found.
+func (c *Codeowners) Validate() []Issue {
+	issues := []Issue{}
+	firstSeen := map[string]int{}
+
+	for i, p := range c.Patterns {
+		if line, ok := firstSeen[p.Pattern]; ok {
+			issues = append(issues, Issue{
+				Severity: SeverityWarning,
+				Line:     p.Line,
+				Token:    p.Pattern,
+				Code:     CodeDuplicate,
+				Message:  fmt.Sprintf("pattern %q duplicates the one on line %d", p.Pattern, line),
+			})
+		} else {
+			firstSeen[p.Pattern] = p.Line
+		}
+
+		if len(p.Owners) == 0 {
+			issues = append(issues, Issue{
+				Severity: SeverityWarning,
+				Line:     p.Line,
+				Token:    p.Pattern,
+				Code:     CodeNoOwners,
+				Message:  fmt.Sprintf("pattern %q has no owners", p.Pattern),
+			})
+		}
+
+		for _, o := range p.Owners {
+			if !ownerPattern.MatchString(o) {
+				issues = append(issues, Issue{
+					Severity: SeverityError,
+					Line:     p.Line,
+					Token:    o,
+					Code:     CodeInvalidOwner,
+					Message:  fmt.Sprintf("%q is not a valid owner (expected @user, @org/team or an email)", o),
+				})
+			}
+		}
+
+		if p.re == nil {
+			issues = append(issues, Issue{
+				Severity: SeverityError,
+				Line:     p.Line,
+				Token:    p.Pattern,
+				Code:     CodeInvalidPattern,
+				Message:  fmt.Sprintf("pattern %q is not a valid glob", p.Pattern),
+			})
+			continue
+		}
+
+		for _, later := range c.Patterns[i+1:] {
+			if isShadowedByLater(p, later) {
+				issues = append(issues, Issue{
+					Severity: SeverityWarning,
+					Line:     p.Line,
+					Token:    p.Pattern,
+					Code:     CodeUnreachable,
+					Message:  fmt.Sprintf("pattern %q on line %d is always shadowed by the later pattern %q on line %d", p.Pattern, p.Line, later.Pattern, later.Line),
+				})
+				break
+			}
+		}
+	}
+
+	return issues
+}
+
 // based on github.com/sabhiram/go-gitignore
 // but modified so that 'dir/*' only matches files in 'dir/'
 func getPattern(line string) *regexp.Regexp {
@@ -0,0 +1,340 @@
+package codeowners
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOwnersGlobalAndDirectoryPatterns(t *testing.T) {
+	co, err := FromReader(strings.NewReader("* @global-owner\ndocs/ @docs-team\n"), "/home/user/myrepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/random.txt", []string{"@global-owner"}},
+		{"/docs/file.md", []string{"@docs-team"}},
+		{"/docs/nested/file.md", []string{"@docs-team"}},
+	}
+	for _, tt := range tests {
+		got := co.Owners(tt.path)
+		if !equalStrings(got, tt.want) {
+			t.Errorf("Owners(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func benchmarkCodeowners(b *testing.B) *Codeowners {
+	var sb strings.Builder
+	for i := 0; i < 2000; i++ {
+		sb.WriteString("src/pkg")
+		sb.WriteString(string(rune('a' + i%26)))
+		sb.WriteString("/**  @team-")
+		sb.WriteString(string(rune('a' + i%26)))
+		sb.WriteString("\n")
+	}
+	co, err := FromReader(strings.NewReader(sb.String()), "/repo")
+	if err != nil {
+		b.Fatal(err)
+	}
+	return co
+}
+
+func BenchmarkOwners(b *testing.B) {
+	co := benchmarkCodeowners(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		co.Owners("/src/pkgm/file.go")
+	}
+}
+
+func TestNegation(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		path    string
+		want    []string
+	}{
+		{
+			name:    "negated double-star directory wins over earlier global",
+			content: "* @global-owner\n!vendor/**/generated.go @nobody\n",
+			path:    "/vendor/a/b/generated.go",
+			want:    nil,
+		},
+		{
+			name:    "negation does not affect paths outside its pattern",
+			content: "* @global-owner\n!vendor/**/generated.go @nobody\n",
+			path:    "/src/main.go",
+			want:    []string{"@global-owner"},
+		},
+		{
+			name:    "negated trailing-slash directory disowns everything under it",
+			content: "* @global-owner\n!build/ @nobody\n",
+			path:    "/build/output.bin",
+			want:    nil,
+		},
+		{
+			name:    "a later non-negated pattern re-owns a path over an earlier negation",
+			content: "!docs/ @nobody\ndocs/public/ @docs-team\n",
+			path:    "/docs/public/file.md",
+			want:    []string{"@docs-team"},
+		},
+		{
+			name:    "negated global disowns everything",
+			content: "!* @nobody\n",
+			path:    "/anything.go",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			co, err := FromReader(strings.NewReader(tt.content), "/repo")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := co.Owners(tt.path)
+			if !equalStrings(got, tt.want) {
+				t.Errorf("Owners(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToFileRoundTripsNegation(t *testing.T) {
+	content := "* @global-owner\n!vendor/**/generated.go @nobody\n"
+	co, err := FromReader(strings.NewReader(content), "/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := co.ToFile(filepath.Join(dir, "CODEOWNERS")); err != nil {
+		t.Fatal(err)
+	}
+
+	reparsed, err := FromFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := reparsed.Owners("/vendor/a/b/generated.go"); got != nil {
+		t.Errorf("Owners(/vendor/a/b/generated.go) = %v, want nil (negation should survive the round trip)", got)
+	}
+	if got, want := reparsed.Owners("/src/main.go"), []string{"@global-owner"}; !equalStrings(got, want) {
+		t.Errorf("Owners(/src/main.go) = %v, want %v", got, want)
+	}
+}
+
+func TestToFileRoundTripsSections(t *testing.T) {
+	content := "[Backend] @backend-team\n^[Frontend][2] @frontend-team\nfrontend/ @fe\n"
+	co, err := FromReader(strings.NewReader(content), "/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := co.ToFile(filepath.Join(dir, "CODEOWNERS")); err != nil {
+		t.Fatal(err)
+	}
+
+	reparsed, err := FromFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sections := reparsed.Sections("/anything.go")
+	backend, ok := sections["Backend"]
+	if !ok {
+		t.Fatal(`round-tripped file is missing "Backend"`)
+	}
+	if want := []string{"@backend-team"}; !equalStrings(backend.Owners, want) {
+		t.Errorf(`Sections()["Backend"].Owners = %v, want %v`, backend.Owners, want)
+	}
+
+	frontend, ok := sections["Frontend"]
+	if !ok {
+		t.Fatal(`round-tripped file is missing "Frontend"`)
+	}
+	if !frontend.Section.Optional {
+		t.Error(`Sections()["Frontend"].Section.Optional = false, want true`)
+	}
+	if frontend.Section.MinApprovers != 2 {
+		t.Errorf(`Sections()["Frontend"].Section.MinApprovers = %d, want 2`, frontend.Section.MinApprovers)
+	}
+
+	if got, want := reparsed.SectionFor("/frontend/app.js", "Frontend"), []string{"@fe"}; !equalStrings(got, want) {
+		t.Errorf(`SectionFor("/frontend/app.js", "Frontend") = %v, want %v`, got, want)
+	}
+}
+
+func TestSectionsFallsBackToDefaultOwnersWithNoPatterns(t *testing.T) {
+	content := "[Backend] @backend-team\n[Frontend] @frontend-team\nfrontend/ @frontend-specialist\n"
+	co, err := FromReader(strings.NewReader(content), "/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Neither section has a pattern matching this path, so both should
+	// fall back to their header's default owners - including "Backend",
+	// which never gets a pattern line of its own.
+	sections := co.Sections("/anything.go")
+	backend, ok := sections["Backend"]
+	if !ok {
+		t.Fatal(`Sections() is missing "Backend", which has no pattern lines of its own`)
+	}
+	if want := []string{"@backend-team"}; !equalStrings(backend.Owners, want) {
+		t.Errorf(`Sections()["Backend"].Owners = %v, want %v`, backend.Owners, want)
+	}
+
+	frontend, ok := sections["Frontend"]
+	if !ok {
+		t.Fatal(`Sections() is missing "Frontend"`)
+	}
+	if want := []string{"@frontend-team"}; !equalStrings(frontend.Owners, want) {
+		t.Errorf(`Sections()["Frontend"].Owners = %v, want %v`, frontend.Owners, want)
+	}
+
+	// Under its own path, Frontend's pattern line beats the default owners.
+	frontend = co.Sections("/frontend/app.js")["Frontend"]
+	if want := []string{"@frontend-specialist"}; !equalStrings(frontend.Owners, want) {
+		t.Errorf(`Sections()["Frontend"].Owners = %v, want %v (its own pattern should beat the default owners)`, frontend.Owners, want)
+	}
+}
+
+func TestDialectRegex(t *testing.T) {
+	content := "^/src/.*\\.go$ @go-team\n# syntax: gitignore\ndocs/ @docs-team\n"
+	co, err := FromReaderWithOptions(strings.NewReader(content), "/repo", Options{Dialect: DialectRegex})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := co.Owners("/src/main.go"), []string{"@go-team"}; !equalStrings(got, want) {
+		t.Errorf("Owners(/src/main.go) = %v, want %v", got, want)
+	}
+	if got := co.Owners("/src/main.js"); got != nil {
+		t.Errorf("Owners(/src/main.js) = %v, want nil", got)
+	}
+	// the "# syntax: gitignore" comment switches the rest of the file back
+	// to the gitignore dialect, so "docs/" is a glob, not a Go regexp.
+	if got, want := co.Owners("/docs/file.md"), []string{"@docs-team"}; !equalStrings(got, want) {
+		t.Errorf("Owners(/docs/file.md) = %v, want %v", got, want)
+	}
+}
+
+func TestToFileRoundTripsDialect(t *testing.T) {
+	co, err := FromReaderWithOptions(strings.NewReader("^/src/.*\\.go$ @go-team\n"), "/repo", Options{Dialect: DialectRegex})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := co.ToFile(filepath.Join(dir, "CODEOWNERS")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the "# syntax: regex" marker a default-dialect re-parse would
+	// treat "^/src/.*\.go$" as a gitignore glob instead of a Go regexp.
+	reparsed, err := FromFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := reparsed.Owners("/src/main.go"), []string{"@go-team"}; !equalStrings(got, want) {
+		t.Errorf("Owners(/src/main.go) = %v, want %v", got, want)
+	}
+	if got := reparsed.Owners("/src/main.js"); got != nil {
+		t.Errorf("Owners(/src/main.js) = %v, want nil", got)
+	}
+}
+
+func TestAllOwnersAndExplain(t *testing.T) {
+	content := "* @global-owner\ndocs/ @docs-team\ndocs/public/ @public-docs-team\n"
+	co, err := FromReader(strings.NewReader(content), "/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all := co.AllOwners("/docs/public/file.md")
+	if len(all) != 3 {
+		t.Fatalf("AllOwners returned %d matches, want 3: %+v", len(all), all)
+	}
+	wantPatterns := []string{"*", "docs/", "docs/public/"}
+	for i, m := range all {
+		if m.Pattern != wantPatterns[i] {
+			t.Errorf("AllOwners()[%d].Pattern = %q, want %q", i, m.Pattern, wantPatterns[i])
+		}
+	}
+
+	if got, want := co.Owners("/docs/public/file.md"), []string{"@public-docs-team"}; !equalStrings(got, want) {
+		t.Errorf("Owners(/docs/public/file.md) = %v, want %v (should agree with the last AllOwners match)", got, want)
+	}
+
+	explanation := co.Explain("/docs/public/file.md")
+	if !strings.Contains(explanation, "docs/public/") || !strings.Contains(explanation, "@public-docs-team") {
+		t.Errorf("Explain() = %q, want it to mention the winning pattern and owner", explanation)
+	}
+	docsOnly, err := FromReader(strings.NewReader("docs/ @docs-team\n"), "/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := docsOnly.Explain("/src/main.go"); !strings.Contains(got, "no matching pattern") {
+		t.Errorf("Explain(unmatched) = %q, want a no-match message", got)
+	}
+}
+
+func TestOwnersManyAgreesWithOwners(t *testing.T) {
+	content := "* @global-owner\n" +
+		"docs/ @docs-team\n" +
+		"/apps/*.js @frontend-team\n" +
+		"src/pkg/** @pkg-team\n" +
+		"!vendor/**/generated.go @nobody\n"
+	co, err := FromReader(strings.NewReader(content), "/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths := []string{
+		"/apps/foo.js",             // anchored dir/*.ext
+		"/x/apps/foo.js",           // same pattern, shouldn't match at depth
+		"/src/docs/file.md",        // unanchored "docs/", matches below root
+		"/src/pkg/sub/file.go",     // recursive "dir/**"
+		"/vendor/a/b/generated.go", // negated
+		"/random.txt",              // only the global pattern
+	}
+
+	got := co.OwnersMany(paths)
+	for _, p := range paths {
+		want := co.Owners(p)
+		if !equalStrings(got[p], want) {
+			t.Errorf("OwnersMany()[%q] = %v, want %v (Owners(%q))", p, got[p], want, p)
+		}
+	}
+}
+
+func BenchmarkOwnersMany(b *testing.B) {
+	co := benchmarkCodeowners(b)
+	paths := make([]string, 500)
+	for i := range paths {
+		paths[i] = "/src/pkgm/file.go"
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		co.OwnersMany(paths)
+	}
+}